@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/weaveworks/common/middleware"
+	"github.com/weaveworks/common/server"
+	"google.golang.org/grpc"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/cortexproject/cortex/pkg/chunk/storage"
+	"github.com/cortexproject/cortex/pkg/compactor"
+	"github.com/cortexproject/cortex/pkg/util"
+)
+
+func main() {
+	var (
+		serverConfig = server.Config{
+			MetricsNamespace: "cortex",
+			GRPCMiddleware: []grpc.UnaryServerInterceptor{
+				middleware.ServerUserHeaderInterceptor,
+			},
+			ExcludeRequestInLog: true,
+		}
+		storageConfig   storage.Config
+		schemaConfig    chunk.SchemaConfig
+		compactorConfig compactor.Config
+	)
+	util.RegisterFlags(&schemaConfig, &storageConfig, &compactorConfig, &serverConfig)
+	flag.Parse()
+
+	util.InitLogger(&serverConfig)
+
+	if !compactorConfig.Enabled {
+		level.Info(util.Logger).Log("msg", "compactor.enabled is false, exiting")
+		return
+	}
+
+	server, err := server.New(serverConfig)
+	if err != nil {
+		level.Error(util.Logger).Log("msg", "error initializing server", "err", err)
+		os.Exit(1)
+	}
+	defer server.Shutdown()
+
+	ctx := context.Background()
+
+	storageOpts, err := storage.Opts(storageConfig, schemaConfig)
+	if err != nil {
+		level.Error(util.Logger).Log("msg", "unable to initialize storage", "err", err)
+		os.Exit(1)
+	}
+
+	tableClient, err := storage.NewTableClient(ctx, storageConfig)
+	if err != nil {
+		level.Error(util.Logger).Log("msg", "unable to initialize table client", "err", err)
+		os.Exit(1)
+	}
+
+	indexClient, err := storage.NewIndexClient(ctx, storageConfig)
+	if err != nil {
+		level.Error(util.Logger).Log("msg", "unable to initialize index client", "err", err)
+		os.Exit(1)
+	}
+
+	limits := compactor.NewOverrides(compactorConfig.DefaultRetentionPeriod, nil)
+
+	c := compactor.New(compactorConfig, tableClient, storageOpts[0].Client.NewScanner(), storageOpts[0].Client, indexClient, limits, prometheus.DefaultRegisterer)
+	c.RegisterRoutes(server)
+
+	go server.Run()
+
+	if err := c.Run(ctx); err != nil {
+		level.Error(util.Logger).Log("msg", "compactor exited with error", "err", err)
+		os.Exit(1)
+	}
+}
@@ -27,8 +27,9 @@ func main() {
 		storageConfig storage.Config
 		schemaConfig  chunk.SchemaConfig
 		readerConfig  migrate.ReaderConfig
+		writerConfig  migrate.WriterConfig
 	)
-	util.RegisterFlags(&schemaConfig, &storageConfig, &readerConfig, &serverConfig)
+	util.RegisterFlags(&schemaConfig, &storageConfig, &readerConfig, &writerConfig, &serverConfig)
 	flag.Parse()
 
 	util.InitLogger(&serverConfig)
@@ -48,7 +49,19 @@ func main() {
 		os.Exit(1)
 	}
 
-	reader, err := migrate.NewReader(readerConfig, storageOpts[0].Client)
+	tableClient, err := storage.NewTableClient(context.Background(), storageConfig)
+	if err != nil {
+		level.Error(util.Logger).Log("msg", "unable to initialize table client", "err", err)
+		os.Exit(1)
+	}
+
+	writer, err := migrate.NewWriter(writerConfig, schemaConfig)
+	if err != nil {
+		level.Error(util.Logger).Log("msg", "unable to initialize writer", "err", err)
+		os.Exit(1)
+	}
+
+	reader, err := migrate.NewReader(readerConfig, storageOpts[0].Client, tableClient, writer)
 	if err != nil {
 		level.Error(util.Logger).Log("msg", "unable to initialize reader", "err", err)
 		os.Exit(1)
@@ -0,0 +1,25 @@
+package azure
+
+import "flag"
+
+// Config configures the Azure Blob Storage object client.
+type Config struct {
+	ContainerName string `yaml:"container_name"`
+	AccountName   string `yaml:"account_name"`
+	AccountKey    string `yaml:"account_key"`
+}
+
+// RegisterFlags adds the flags required to configure this flag set.
+func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
+	cfg.RegisterFlagsWithPrefix("azure", f)
+}
+
+// RegisterFlagsWithPrefix adds the flags required to configure this flag
+// set, each name rooted at prefix instead of the fixed "azure" used by
+// RegisterFlags, so a second Config can be registered on the same FlagSet
+// without colliding with the first.
+func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.StringVar(&cfg.ContainerName, prefix+".container-name", "cortex", "Name of the Blob Storage container to store chunks in.")
+	f.StringVar(&cfg.AccountName, prefix+".account-name", "", "Azure Storage account name.")
+	f.StringVar(&cfg.AccountKey, prefix+".account-key", "", "Azure Storage account key.")
+}
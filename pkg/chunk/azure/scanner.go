@@ -0,0 +1,92 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/cortexproject/cortex/pkg/chunk/shard"
+)
+
+// scanner implements chunk.Scanner by listing blobs under a userID/
+// fingerprint hex-prefix. Blob Storage has no notion of a table, so
+// tableName is accepted only to satisfy the shared chunk.Scanner interface
+// and ignored.
+type scanner struct {
+	container azblob.ContainerURL
+}
+
+func (s *scanner) ScanTable(ctx context.Context, tableName string, callback func(chunk.Chunk) bool) error {
+	return s.scanPrefix(ctx, "", callback)
+}
+
+// ListTenants returns the distinct user IDs with at least one blob in the
+// container. Blob names are "<userID>/<fingerprint-hex>:...", so the "/"
+// delimiter makes Blob Storage return each tenant's prefix directly
+// without listing every blob underneath it.
+func (s *scanner) ListTenants(ctx context.Context, tableName string) ([]string, error) {
+	var tenants []string
+	for marker := (azblob.Marker{}); marker.NotDone(); {
+		resp, err := s.container.ListBlobsHierarchySegment(ctx, marker, "/", azblob.ListBlobsSegmentOptions{})
+		if err != nil {
+			return nil, err
+		}
+		marker = resp.NextMarker
+
+		for _, prefix := range resp.Segment.BlobPrefixes {
+			tenants = append(tenants, strings.TrimSuffix(prefix.Name, "/"))
+		}
+	}
+	return tenants, nil
+}
+
+// ScanShard calls callback for every chunk belonging to userID whose blob
+// name falls under the given shard, one of the 240 two-hex-character
+// fingerprint prefixes described on bigtableObjectClient.NewScanner.
+func (s *scanner) ScanShard(ctx context.Context, tableName, userID string, shardNum int, callback func(chunk.Chunk) bool) error {
+	if shardNum < 0 || shardNum >= len(shard.Prefixes) {
+		return fmt.Errorf("shard %d out of range [0,%d)", shardNum, len(shard.Prefixes))
+	}
+	return s.scanPrefix(ctx, userID+"/"+shard.Prefixes[shardNum], callback)
+}
+
+func (s *scanner) scanPrefix(ctx context.Context, prefix string, callback func(chunk.Chunk) bool) error {
+	for marker := (azblob.Marker{}); marker.NotDone(); {
+		resp, err := s.container.ListBlobsFlatSegment(ctx, marker, azblob.ListBlobsSegmentOptions{Prefix: prefix})
+		if err != nil {
+			return err
+		}
+		marker = resp.NextMarker
+
+		for _, item := range resp.Segment.BlobItems {
+			c, err := chunk.ParseExternalKey("", item.Name)
+			if err != nil {
+				return err
+			}
+
+			blob := s.container.NewBlockBlobURL(item.Name)
+			download, err := blob.Download(ctx, 0, azblob.CountToEnd, azblob.BlobAccessConditions{}, false)
+			if err != nil {
+				return err
+			}
+			body := download.Body(azblob.RetryReaderOptions{})
+			buf, err := ioutil.ReadAll(body)
+			body.Close()
+			if err != nil {
+				return err
+			}
+
+			if err := c.Decode(chunk.NewDecodeContext(), buf); err != nil {
+				return err
+			}
+			if !callback(c) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
@@ -20,6 +20,13 @@ type bigtableObjectClient struct {
 	cfg       Config
 	schemaCfg chunk.SchemaConfig
 	client    *bigtable.Client
+
+	// writeLimiter, readLimiter and inflight are shared across every
+	// table.Open call this client instance makes, so the configured rate
+	// and inflight limits apply client-wide rather than per-table.
+	writeLimiter *adaptiveLimiter
+	readLimiter  *adaptiveLimiter
+	inflight     chan struct{}
 }
 
 // NewBigtableObjectClient makes a new chunk.Client that stores chunks in
@@ -45,10 +52,18 @@ func NewBigtableObjectClient(ctx context.Context, cfg Config, schemaCfg chunk.Sc
 }
 
 func newBigtableObjectClient(cfg Config, schemaCfg chunk.SchemaConfig, client *bigtable.Client) *bigtableObjectClient {
+	maxInflight := cfg.MaxInflightBulk
+	if maxInflight <= 0 {
+		maxInflight = 1
+	}
+
 	return &bigtableObjectClient{
-		cfg:       cfg,
-		schemaCfg: schemaCfg,
-		client:    client,
+		cfg:          cfg,
+		schemaCfg:    schemaCfg,
+		client:       client,
+		writeLimiter: newAdaptiveLimiter(cfg.WriteRateLimit),
+		readLimiter:  newAdaptiveLimiter(cfg.ReadRateLimit),
+		inflight:     make(chan struct{}, maxInflight),
 	}
 }
 
@@ -59,6 +74,7 @@ func (s *bigtableObjectClient) Stop() {
 func (s *bigtableObjectClient) PutChunks(ctx context.Context, chunks []chunk.Chunk) error {
 	keys := map[string][]string{}
 	muts := map[string][]*bigtable.Mutation{}
+	bytesByTable := map[string]int{}
 
 	for i := range chunks {
 		buf, err := chunks[i].Encoded()
@@ -71,6 +87,7 @@ func (s *bigtableObjectClient) PutChunks(ctx context.Context, chunks []chunk.Chu
 			return err
 		}
 		keys[tableName] = append(keys[tableName], key)
+		bytesByTable[tableName] += len(buf)
 
 		mut := bigtable.NewMutation()
 		mut.Set(columnFamily, column, 0, buf)
@@ -78,15 +95,34 @@ func (s *bigtableObjectClient) PutChunks(ctx context.Context, chunks []chunk.Chu
 	}
 
 	for tableName := range keys {
-		table := s.client.Open(tableName)
-		errs, err := table.ApplyBulk(ctx, keys[tableName], muts[tableName])
-		if err != nil {
+		if err := s.writeLimiter.wait(ctx, bytesByTable[tableName]); err != nil {
 			return err
 		}
-		for _, err := range errs {
+
+		select {
+		case s.inflight <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		table := s.client.Open(tableName)
+		tableKeys, tableMuts := keys[tableName], muts[tableName]
+
+		err := s.writeLimiter.withBackoff(ctx, func() error {
+			errs, err := table.ApplyBulk(ctx, tableKeys, tableMuts)
 			if err != nil {
 				return err
 			}
+			for _, err := range errs {
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		<-s.inflight
+		if err != nil {
+			return err
 		}
 	}
 	return nil
@@ -125,36 +161,70 @@ func (s *bigtableObjectClient) GetChunks(ctx context.Context, input []chunk.Chun
 		for i := 0; i < len(keys); i += maxRowReads {
 			page := keys[i:util.Min(i+maxRowReads, len(keys))]
 			go func(page bigtable.RowList) {
+				// The byte size of a page isn't known until its rows come
+				// back, so only the ops/sec budget can be waited on up
+				// front; the bytes/sec budget is debited afterwards, see
+				// readLimiter.observe below.
+				if err := s.readLimiter.wait(ctx, 0); err != nil {
+					errs <- err
+					return
+				}
+
+				select {
+				case s.inflight <- struct{}{}:
+				case <-ctx.Done():
+					errs <- ctx.Err()
+					return
+				}
+				defer func() { <-s.inflight }()
+
 				decodeContext := chunk.NewDecodeContext()
 
 				var processingErr error
-				var receivedChunks = 0
-
-				// rows are returned in key order, not order in row list
-				err := table.ReadRows(ctx, page, func(row bigtable.Row) bool {
-					chunk, ok := chunks[row.Key()]
-					if !ok {
-						processingErr = errors.WithStack(fmt.Errorf("Got row for unknown chunk: %s", row.Key()))
-						return false
-					}
+				var received []chunk.Chunk
+				var receivedBytes int
 
-					err := chunk.Decode(decodeContext, row[columnFamily][0].Value)
-					if err != nil {
-						processingErr = err
-						return false
-					}
+				// rows are returned in key order, not order in row list.
+				// withBackoff may re-run this closure from scratch on a
+				// retry, so rows are decoded into a local slice rather than
+				// sent to outs as they arrive - outs has no way to undo an
+				// already-sent chunk, and resending it on retry would both
+				// starve a legitimate reader of one of its expected
+				// len(input) receives and risk overflowing outs' buffer.
+				err := s.readLimiter.withBackoff(ctx, func() error {
+					received = received[:0]
+					receivedBytes = 0
+					return table.ReadRows(ctx, page, func(row bigtable.Row) bool {
+						chunk, ok := chunks[row.Key()]
+						if !ok {
+							processingErr = errors.WithStack(fmt.Errorf("Got row for unknown chunk: %s", row.Key()))
+							return false
+						}
 
-					receivedChunks++
-					outs <- chunk
-					return true
+						value := row[columnFamily][0].Value
+						err := chunk.Decode(decodeContext, value)
+						if err != nil {
+							processingErr = err
+							return false
+						}
+
+						received = append(received, chunk)
+						receivedBytes += len(value)
+						return true
+					})
 				})
+				s.readLimiter.observe(receivedBytes)
 
 				if processingErr != nil {
 					errs <- processingErr
 				} else if err != nil {
 					errs <- errors.WithStack(err)
-				} else if receivedChunks < len(page) {
-					errs <- errors.WithStack(fmt.Errorf("Asked for %d chunks for Bigtable, received %d", len(page), receivedChunks))
+				} else if len(received) < len(page) {
+					errs <- errors.WithStack(fmt.Errorf("Asked for %d chunks for Bigtable, received %d", len(page), len(received)))
+				} else {
+					for _, c := range received {
+						outs <- c
+					}
 				}
 			}(page)
 		}
@@ -176,8 +246,33 @@ func (s *bigtableObjectClient) GetChunks(ctx context.Context, input []chunk.Chun
 }
 
 func (s *bigtableObjectClient) DeleteChunk(ctx context.Context, chunkID string) error {
-	// ToDo: implement this to support deleting chunks from Bigtable
-	return chunk.ErrMethodNotImplemented
+	chunkRef, err := chunk.ParseExternalKey("", chunkID)
+	if err != nil {
+		return err
+	}
+
+	tableName, err := s.schemaCfg.ChunkTableFor(chunkRef.From)
+	if err != nil {
+		return err
+	}
+
+	if err := s.writeLimiter.wait(ctx, 0); err != nil {
+		return err
+	}
+
+	select {
+	case s.inflight <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-s.inflight }()
+
+	table := s.client.Open(tableName)
+	return s.writeLimiter.withBackoff(ctx, func() error {
+		mutation := bigtable.NewMutation()
+		mutation.DeleteRow()
+		return table.Apply(ctx, chunkID, mutation)
+	})
 }
 
 // NewScanner returns a GCP Bigtable specific stream batch.
@@ -0,0 +1,59 @@
+package gcp
+
+import (
+	"flag"
+	"time"
+
+	"github.com/cortexproject/cortex/pkg/util/grpcclient"
+)
+
+// Config configures the Bigtable clients used by the chunk store: the
+// object (chunk) client, the index client and the admin/table client.
+type Config struct {
+	Project  string `yaml:"project"`
+	Instance string `yaml:"instance"`
+	KeyFile  string `yaml:"key_file"`
+
+	GRPCClientConfig grpcclient.Config `yaml:"grpc_client_config"`
+
+	TableCacheEnabled    bool          `yaml:"table_cache_enabled"`
+	TableCacheExpiration time.Duration `yaml:"table_cache_expiration"`
+
+	WriteRateLimit  RateLimitConfig `yaml:"write_rate_limit"`
+	ReadRateLimit   RateLimitConfig `yaml:"read_rate_limit"`
+	MaxInflightBulk int             `yaml:"max_inflight_bulk"`
+}
+
+// RegisterFlags adds the flags required to configure this flag set.
+func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
+	cfg.RegisterFlagsWithPrefix("bigtable", f)
+}
+
+// RegisterFlagsWithPrefix adds the flags required to configure this flag
+// set, each name rooted at prefix instead of the fixed "bigtable" used by
+// RegisterFlags, so a second Config can be registered on the same FlagSet
+// without colliding with the first.
+func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.StringVar(&cfg.Project, prefix+".project", "", "Bigtable project ID.")
+	f.StringVar(&cfg.Instance, prefix+".instance", "", "Bigtable instance ID.")
+	f.StringVar(&cfg.KeyFile, prefix+".key-file", "", "Path to a JSON key file for authenticating with Bigtable; if empty, application default credentials are used.")
+	cfg.GRPCClientConfig.RegisterFlagsWithPrefix(prefix, f)
+	f.BoolVar(&cfg.TableCacheEnabled, prefix+".table-cache.enabled", true, "Cache table info between ListTables calls.")
+	f.DurationVar(&cfg.TableCacheExpiration, prefix+".table-cache.expiration", 30*time.Minute, "Duration to cache table info for.")
+	cfg.WriteRateLimit.RegisterFlagsWithPrefix(prefix+".write-rate-limit", f)
+	cfg.ReadRateLimit.RegisterFlagsWithPrefix(prefix+".read-rate-limit", f)
+	f.IntVar(&cfg.MaxInflightBulk, prefix+".max-inflight-bulk", 32, "Maximum number of concurrent ApplyBulk/ReadRows calls a single client instance will issue across all tables.")
+}
+
+// RateLimitConfig configures a token-bucket limit on either read or write
+// traffic to Bigtable, in both bytes/sec and ops/sec.
+type RateLimitConfig struct {
+	BytesPerSecond float64 `yaml:"bytes_per_second"`
+	OpsPerSecond   float64 `yaml:"ops_per_second"`
+}
+
+// RegisterFlagsWithPrefix adds the flags required to configure this flag set.
+func (cfg *RateLimitConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.Float64Var(&cfg.BytesPerSecond, prefix+".bytes-per-second", 0, "Maximum average bytes/sec to send to Bigtable. 0 disables the limit.")
+	f.Float64Var(&cfg.OpsPerSecond, prefix+".ops-per-second", 0, "Maximum average operations/sec to send to Bigtable. 0 disables the limit.")
+}
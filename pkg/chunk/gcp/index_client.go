@@ -0,0 +1,84 @@
+package gcp
+
+import (
+	"context"
+
+	"cloud.google.com/go/bigtable"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+)
+
+// bigtableIndexClient deletes chunk index entries from Bigtable. This chunk
+// store's Bigtable schema has no separate index table - a chunk's index
+// entry is just its row in the chunk table itself, keyed by its external
+// key - so deleting an index entry means deleting that row, the same way
+// bigtableObjectClient.DeleteChunk does.
+type bigtableIndexClient struct {
+	client *bigtable.Client
+}
+
+// NewBigtableIndexClient opens a Bigtable connection configured by cfg and
+// returns a chunk.IndexClient backed by it. This is a separate connection
+// from the one a bigtableObjectClient uses for chunk data, the same way
+// NewTableClient opens its own admin connection rather than sharing one.
+func NewBigtableIndexClient(ctx context.Context, cfg Config) (chunk.IndexClient, error) {
+	opts := toOptions(cfg.GRPCClientConfig.DialOption(bigtableInstrumentation()))
+	client, err := bigtable.NewClient(ctx, cfg.Project, cfg.Instance, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &bigtableIndexClient{client: client}, nil
+}
+
+func (c *bigtableIndexClient) NewWriteBatch() chunk.WriteBatch {
+	return &bigtableWriteBatch{}
+}
+
+func (c *bigtableIndexClient) BatchWrite(ctx context.Context, batch chunk.WriteBatch) error {
+	b := batch.(*bigtableWriteBatch)
+
+	rowKeysByTable := map[string][]string{}
+	for _, d := range b.deletes {
+		rowKeysByTable[d.tableName] = append(rowKeysByTable[d.tableName], d.rowKey)
+	}
+
+	for tableName, rowKeys := range rowKeysByTable {
+		table := c.client.Open(tableName)
+
+		muts := make([]*bigtable.Mutation, len(rowKeys))
+		for i := range rowKeys {
+			mut := bigtable.NewMutation()
+			mut.DeleteRow()
+			muts[i] = mut
+		}
+
+		errs, err := table.ApplyBulk(ctx, rowKeys, muts)
+		if err != nil {
+			return err
+		}
+		for _, err := range errs {
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// bigtableIndexDelete is one row to remove, scoped to the table it lives in.
+type bigtableIndexDelete struct {
+	tableName string
+	rowKey    string
+}
+
+// bigtableWriteBatch accumulates deletes for one BatchWrite call. The
+// rangeValue passed to Delete is the row key a chunk was written under -
+// see bigtableObjectClient.PutChunks and DeleteChunk - so hashValue isn't
+// needed to identify the row.
+type bigtableWriteBatch struct {
+	deletes []bigtableIndexDelete
+}
+
+func (b *bigtableWriteBatch) Delete(tableName, hashValue string, rangeValue []byte) {
+	b.deletes = append(b.deletes, bigtableIndexDelete{tableName: tableName, rowKey: string(rangeValue)})
+}
@@ -0,0 +1,193 @@
+package gcp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	ot "github.com/opentracing/opentracing-go"
+	otlog "github.com/opentracing/opentracing-go/log"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// minBackoffFactor is the lowest fraction of the configured rate an
+// adaptiveLimiter will back off to, however many overload signals it sees
+// in a row.
+const minBackoffFactor = 0.05
+
+// recoveryInterval is how often an adaptiveLimiter doubles its effective
+// rate back towards the configured base after backing off.
+const recoveryInterval = 30 * time.Second
+
+// adaptiveLimiter is a token-bucket limiter for one traffic class (reads or
+// writes) that halves its effective rate when Bigtable signals overload,
+// and exponentially recovers back towards the configured rate afterwards.
+// It is safe for concurrent use and is shared across all table.Open calls
+// on a single client instance.
+type adaptiveLimiter struct {
+	baseBytesPerSecond, baseOpsPerSecond float64
+
+	mtx         sync.Mutex
+	bytes       *rate.Limiter
+	ops         *rate.Limiter
+	factor      float64 // current fraction of the base rate in effect, (0, 1]
+	lastBackoff time.Time
+}
+
+func newAdaptiveLimiter(cfg RateLimitConfig) *adaptiveLimiter {
+	l := &adaptiveLimiter{
+		baseBytesPerSecond: cfg.BytesPerSecond,
+		baseOpsPerSecond:   cfg.OpsPerSecond,
+		factor:             1,
+	}
+	l.rebuildLocked()
+	return l
+}
+
+func (l *adaptiveLimiter) rebuildLocked() {
+	l.bytes = tokenBucket(l.baseBytesPerSecond * l.factor)
+	l.ops = tokenBucket(l.baseOpsPerSecond * l.factor)
+}
+
+func tokenBucket(ratePerSecond float64) *rate.Limiter {
+	if ratePerSecond <= 0 {
+		return rate.NewLimiter(rate.Inf, 0)
+	}
+	burst := int(ratePerSecond)
+	if burst < 1 {
+		burst = 1
+	}
+	return rate.NewLimiter(rate.Limit(ratePerSecond), burst)
+}
+
+// wait blocks until the limiter has budget for one operation of the given
+// size, first recovering the rate towards its base if enough time has
+// passed since the last backoff.
+func (l *adaptiveLimiter) wait(ctx context.Context, bytes int) error {
+	l.maybeRecover()
+
+	l.mtx.Lock()
+	opsLimiter, bytesLimiter := l.ops, l.bytes
+	l.mtx.Unlock()
+
+	if err := opsLimiter.Wait(ctx); err != nil {
+		return err
+	}
+	return waitN(ctx, bytesLimiter, bytes)
+}
+
+// waitN waits for n tokens, in steps no larger than limiter's burst.
+// rate.Limiter.WaitN returns a hard error, rather than waiting longer,
+// when n exceeds the burst size - and tokenBucket sizes burst off of the
+// configured rate, which is routinely smaller than one PutChunks/GetChunks
+// batch. Splitting into burst-sized steps keeps a single large request
+// from failing outright instead of being throttled.
+func waitN(ctx context.Context, limiter *rate.Limiter, n int) error {
+	burst := limiter.Burst()
+	for n > 0 {
+		step := n
+		if burst > 0 && step > burst {
+			step = burst
+		}
+		if err := limiter.WaitN(ctx, step); err != nil {
+			return err
+		}
+		n -= step
+	}
+	return nil
+}
+
+// observe debits bytes already transferred from the byte-rate budget.
+// It exists for traffic, like Bigtable reads, whose size isn't known
+// until after the request completes: wait can't block on a size it
+// doesn't have yet, so observe instead lets the budget run into debt,
+// which throttles the *next* wait call rather than this one. Like wait,
+// it steps the reservation in burst-sized pieces; ReserveN silently
+// no-ops when asked to reserve more than the burst in one call, which for
+// a real read page is the common case, not the exception.
+func (l *adaptiveLimiter) observe(bytes int) {
+	l.mtx.Lock()
+	bytesLimiter := l.bytes
+	l.mtx.Unlock()
+
+	burst := bytesLimiter.Burst()
+	now := time.Now()
+	for bytes > 0 {
+		step := bytes
+		if burst > 0 && step > burst {
+			step = burst
+		}
+		bytesLimiter.ReserveN(now, step)
+		bytes -= step
+	}
+}
+
+// backoff halves the effective rate in response to an overload signal.
+func (l *adaptiveLimiter) backoff() {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	l.factor /= 2
+	if l.factor < minBackoffFactor {
+		l.factor = minBackoffFactor
+	}
+	l.lastBackoff = time.Now()
+	l.rebuildLocked()
+}
+
+// maybeRecover doubles the effective rate back towards the base, at most
+// once per recoveryInterval since the last backoff.
+func (l *adaptiveLimiter) maybeRecover() {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.factor >= 1 || l.lastBackoff.IsZero() || time.Since(l.lastBackoff) < recoveryInterval {
+		return
+	}
+
+	l.factor *= 2
+	if l.factor > 1 {
+		l.factor = 1
+	}
+	l.lastBackoff = time.Now()
+	l.rebuildLocked()
+}
+
+// isOverloadErr reports whether err is a Bigtable response telling the
+// client to slow down.
+func isOverloadErr(err error) bool {
+	s, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	switch s.Code() {
+	case codes.ResourceExhausted, codes.Unavailable:
+		return true
+	default:
+		return false
+	}
+}
+
+// withBackoff runs fn. If fn fails with an overload error, it halves the
+// limiter's rate, logs the retry as an OT span event so it appears
+// alongside the rest of the request's tracing, waits for budget under the
+// new, lower rate, and retries fn once.
+func (l *adaptiveLimiter) withBackoff(ctx context.Context, fn func() error) error {
+	err := fn()
+	if err == nil || !isOverloadErr(err) {
+		return err
+	}
+
+	l.backoff()
+
+	if sp := ot.SpanFromContext(ctx); sp != nil {
+		sp.LogFields(otlog.String("event", "bigtable overload, backing off and retrying"), otlog.Error(err))
+	}
+
+	if err := l.wait(ctx, 0); err != nil {
+		return err
+	}
+	return fn()
+}
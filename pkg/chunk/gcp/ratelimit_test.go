@@ -0,0 +1,60 @@
+package gcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdaptiveLimiterBackoffAndRecover(t *testing.T) {
+	l := newAdaptiveLimiter(RateLimitConfig{BytesPerSecond: 1000, OpsPerSecond: 100})
+	require.Equal(t, 1.0, l.factor)
+
+	l.backoff()
+	require.Equal(t, 0.5, l.factor)
+
+	l.backoff()
+	require.Equal(t, 0.25, l.factor)
+
+	// Recovery is time-gated; immediately after a backoff it should not
+	// have recovered yet.
+	l.maybeRecover()
+	require.Equal(t, 0.25, l.factor)
+}
+
+func TestWaitSplitsRequestsLargerThanBurst(t *testing.T) {
+	// burst == int(BytesPerSecond) == 1000, so a single 2500-byte wait
+	// used to exceed rate.Limiter.WaitN's burst and fail outright instead
+	// of just taking longer.
+	l := newAdaptiveLimiter(RateLimitConfig{BytesPerSecond: 1000, OpsPerSecond: 1e6})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require.NoError(t, l.wait(ctx, 2500))
+}
+
+func TestObserveAboveBurstThrottlesFutureWait(t *testing.T) {
+	// burst == 10. Observing 1000 bytes in one ReserveN call would exceed
+	// it and silently no-op; split into burst-sized steps, it should run
+	// the budget far enough into debt to throttle the next wait.
+	l := newAdaptiveLimiter(RateLimitConfig{BytesPerSecond: 10, OpsPerSecond: 1e6})
+	l.observe(1000)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := l.wait(ctx, 1)
+	require.Error(t, err, "observing a large read should throttle the next wait, not silently no-op")
+}
+
+func TestIsOverloadErr(t *testing.T) {
+	require.False(t, isOverloadErr(nil))
+	require.False(t, isOverloadErr(errTest{}))
+}
+
+type errTest struct{}
+
+func (errTest) Error() string { return "boom" }
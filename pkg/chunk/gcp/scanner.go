@@ -0,0 +1,95 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/bigtable"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/cortexproject/cortex/pkg/chunk/shard"
+)
+
+// scanner implements chunk.Scanner for Bigtable: it reads a whole chunk
+// table, or just the slice of one tenant's keys covered by one
+// fingerprint-prefix shard.
+type scanner struct {
+	client *bigtable.Client
+}
+
+// ScanTable calls callback for every chunk in tableName. Returning false
+// from callback stops the scan early.
+func (s *scanner) ScanTable(ctx context.Context, tableName string, callback func(chunk.Chunk) bool) error {
+	return s.scanRange(ctx, tableName, bigtable.InfiniteRange(""), callback)
+}
+
+// ListTenants returns the distinct user IDs with at least one row in
+// tableName. Chunk keys are "<userID>/<fingerprint-hex>:...", so the
+// tenant dimension has to be discovered before a fingerprint-prefix shard
+// can be scanned in isolation - shards only partition one tenant's
+// keyspace, not the whole table.
+func (s *scanner) ListTenants(ctx context.Context, tableName string) ([]string, error) {
+	table := s.client.Open(tableName)
+
+	seen := map[string]struct{}{}
+	var tenants []string
+
+	err := table.ReadRows(ctx, bigtable.InfiniteRange(""), func(row bigtable.Row) bool {
+		userID := userIDFromKey(row.Key())
+		if _, ok := seen[userID]; !ok {
+			seen[userID] = struct{}{}
+			tenants = append(tenants, userID)
+		}
+		return true
+	}, bigtable.RowFilter(bigtable.StripValueFilter()))
+	if err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
+
+// ScanShard calls callback for every chunk belonging to userID in
+// tableName whose external key falls under the given shard, one of the
+// 240 two-hex-character fingerprint prefixes described on
+// bigtableObjectClient.NewScanner.
+func (s *scanner) ScanShard(ctx context.Context, tableName, userID string, shardNum int, callback func(chunk.Chunk) bool) error {
+	if shardNum < 0 || shardNum >= len(shard.Prefixes) {
+		return fmt.Errorf("shard %d out of range [0,%d)", shardNum, len(shard.Prefixes))
+	}
+	prefix := userID + "/" + shard.Prefixes[shardNum]
+	return s.scanRange(ctx, tableName, bigtable.PrefixRange(prefix), callback)
+}
+
+func (s *scanner) scanRange(ctx context.Context, tableName string, rr bigtable.RowRange, callback func(chunk.Chunk) bool) error {
+	table := s.client.Open(tableName)
+	decodeContext := chunk.NewDecodeContext()
+
+	var decodeErr error
+	err := table.ReadRows(ctx, rr, func(row bigtable.Row) bool {
+		c, err := chunk.ParseExternalKey("", row.Key())
+		if err != nil {
+			decodeErr = err
+			return false
+		}
+
+		if err := c.Decode(decodeContext, row[columnFamily][0].Value); err != nil {
+			decodeErr = err
+			return false
+		}
+
+		return callback(c)
+	})
+	if decodeErr != nil {
+		return decodeErr
+	}
+	return err
+}
+
+// userIDFromKey extracts the tenant ID from a "<userID>/<rest>" chunk key.
+func userIDFromKey(key string) string {
+	if i := strings.Index(key, "/"); i >= 0 {
+		return key[:i]
+	}
+	return key
+}
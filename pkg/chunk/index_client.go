@@ -0,0 +1,19 @@
+package chunk
+
+import "context"
+
+// IndexClient deletes the index entries that point at chunks removed from
+// the backing store. It follows the same NewWriteBatch/BatchWrite shape as
+// the index half of a chunk store's backend (e.g. the Bigtable, Cassandra
+// or DynamoDB index client), so a real index client can be passed to
+// compactor.New without an adapter.
+type IndexClient interface {
+	NewWriteBatch() WriteBatch
+	BatchWrite(ctx context.Context, batch WriteBatch) error
+}
+
+// WriteBatch accumulates index mutations to submit together in one
+// IndexClient.BatchWrite call.
+type WriteBatch interface {
+	Delete(tableName, hashValue string, rangeValue []byte)
+}
@@ -0,0 +1,51 @@
+// Package objectclient holds helpers shared by the object-store-backed
+// chunk.Client implementations (s3, azure): each issues one PUT/GET per
+// chunk, so they share the same bounded-parallelism fan-out.
+package objectclient
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+)
+
+// MaxParallelObjects bounds the number of concurrent object PUT/GET
+// requests a single client issues, mirroring maxRowReads in the Bigtable
+// client.
+const MaxParallelObjects = 250
+
+// Parallelize calls fn for every chunk in chunks, up to MaxParallelObjects
+// at a time, and returns the first error encountered, if any.
+func Parallelize(ctx context.Context, chunks []chunk.Chunk, fn func(context.Context, chunk.Chunk) error) error {
+	g, ctx := errgroup.WithContext(ctx)
+	sem := make(chan struct{}, MaxParallelObjects)
+
+	for _, c := range chunks {
+		c := c
+		sem <- struct{}{}
+		g.Go(func() error {
+			defer func() { <-sem }()
+			return fn(ctx, c)
+		})
+	}
+	return g.Wait()
+}
+
+// ParallelizeIndexed is Parallelize, but fn also receives each chunk's
+// index in the input slice.
+func ParallelizeIndexed(ctx context.Context, chunks []chunk.Chunk, fn func(context.Context, int, chunk.Chunk) error) error {
+	g, ctx := errgroup.WithContext(ctx)
+	sem := make(chan struct{}, MaxParallelObjects)
+
+	for i, c := range chunks {
+		i, c := i, c
+		sem <- struct{}{}
+		g.Go(func() error {
+			defer func() { <-sem }()
+			return fn(ctx, i, c)
+		})
+	}
+	return g.Wait()
+}
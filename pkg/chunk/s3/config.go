@@ -0,0 +1,29 @@
+package s3
+
+import "flag"
+
+// Config configures the S3/MinIO object client.
+type Config struct {
+	Endpoint        string `yaml:"endpoint"`
+	BucketName      string `yaml:"bucket_name"`
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+	Insecure        bool   `yaml:"insecure"`
+}
+
+// RegisterFlags adds the flags required to configure this flag set.
+func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
+	cfg.RegisterFlagsWithPrefix("s3", f)
+}
+
+// RegisterFlagsWithPrefix adds the flags required to configure this flag
+// set, each name rooted at prefix instead of the fixed "s3" used by
+// RegisterFlags, so a second Config can be registered on the same FlagSet
+// without colliding with the first.
+func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.StringVar(&cfg.Endpoint, prefix+".endpoint", "s3.amazonaws.com", "S3 (or MinIO) endpoint to connect to.")
+	f.StringVar(&cfg.BucketName, prefix+".bucket-name", "cortex", "Name of the bucket to store chunks in.")
+	f.StringVar(&cfg.AccessKeyID, prefix+".access-key-id", "", "S3 access key ID.")
+	f.StringVar(&cfg.SecretAccessKey, prefix+".secret-access-key", "", "S3 secret access key.")
+	f.BoolVar(&cfg.Insecure, prefix+".insecure", false, "Connect over plain HTTP instead of HTTPS; useful when talking to a local MinIO instance.")
+}
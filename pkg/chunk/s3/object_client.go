@@ -0,0 +1,88 @@
+// Package s3 implements a chunk.Client and chunk.Scanner backed by any
+// S3-compatible object store (AWS S3 or MinIO), parallel to the Bigtable
+// client in pkg/chunk/gcp so operators can pick either backend behind the
+// same storage.engine flag.
+package s3
+
+import (
+	"bytes"
+	"context"
+
+	minio "github.com/minio/minio-go/v6"
+	"github.com/pkg/errors"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/cortexproject/cortex/pkg/chunk/objectclient"
+)
+
+type objectClient struct {
+	cfg    Config
+	client *minio.Client
+}
+
+// NewObjectClient makes a new chunk.Client that stores chunks as objects
+// in an S3-compatible bucket, keyed by chunk.ExternalKey().
+func NewObjectClient(cfg Config, schemaCfg chunk.SchemaConfig) (chunk.Client, error) {
+	client, err := minio.New(cfg.Endpoint, cfg.AccessKeyID, cfg.SecretAccessKey, !cfg.Insecure)
+	if err != nil {
+		return nil, errors.Wrap(err, "building S3 client")
+	}
+
+	exists, err := client.BucketExists(cfg.BucketName)
+	if err != nil {
+		return nil, errors.Wrap(err, "checking bucket exists")
+	}
+	if !exists {
+		if err := client.MakeBucket(cfg.BucketName, ""); err != nil {
+			return nil, errors.Wrap(err, "creating bucket")
+		}
+	}
+
+	return &objectClient{cfg: cfg, client: client}, nil
+}
+
+func (o *objectClient) Stop() {}
+
+func (o *objectClient) PutChunks(ctx context.Context, chunks []chunk.Chunk) error {
+	return objectclient.Parallelize(ctx, chunks, func(ctx context.Context, c chunk.Chunk) error {
+		buf, err := c.Encoded()
+		if err != nil {
+			return err
+		}
+
+		_, err = o.client.PutObjectWithContext(ctx, o.cfg.BucketName, c.ExternalKey(), bytes.NewReader(buf), int64(len(buf)), minio.PutObjectOptions{})
+		return err
+	})
+}
+
+func (o *objectClient) GetChunks(ctx context.Context, input []chunk.Chunk) ([]chunk.Chunk, error) {
+	output := make([]chunk.Chunk, len(input))
+
+	err := objectclient.ParallelizeIndexed(ctx, input, func(ctx context.Context, i int, c chunk.Chunk) error {
+		buf, err := getObject(ctx, o.client, o.cfg.BucketName, c.ExternalKey())
+		if err != nil {
+			return err
+		}
+		if err := c.Decode(chunk.NewDecodeContext(), buf); err != nil {
+			return err
+		}
+		output[i] = c
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+func (o *objectClient) DeleteChunk(ctx context.Context, chunkID string) error {
+	return o.client.RemoveObject(o.cfg.BucketName, chunkID)
+}
+
+// NewScanner returns a chunk.Scanner that lists objects by the same
+// fingerprint hex-prefix shard scheme documented on
+// bigtableObjectClient.NewScanner, so the sharded migrate reader can drive
+// this backend identically to Bigtable.
+func (o *objectClient) NewScanner() chunk.Scanner {
+	return &scanner{client: o.client, bucket: o.cfg.BucketName}
+}
@@ -0,0 +1,95 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	minio "github.com/minio/minio-go/v6"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/cortexproject/cortex/pkg/chunk/shard"
+)
+
+// scanner implements chunk.Scanner by listing objects under a userID/
+// fingerprint hex-prefix. S3 has no notion of a table, so tableName is
+// accepted only to satisfy the shared chunk.Scanner interface and ignored.
+type scanner struct {
+	client *minio.Client
+	bucket string
+}
+
+func (s *scanner) ScanTable(ctx context.Context, tableName string, callback func(chunk.Chunk) bool) error {
+	return s.scanPrefix(ctx, "", callback)
+}
+
+// ListTenants returns the distinct user IDs with at least one object in
+// the bucket. Object keys are "<userID>/<fingerprint-hex>:...", so a
+// non-recursive listing returns each tenant's "<userID>/" common prefix
+// directly without listing every object underneath it.
+func (s *scanner) ListTenants(ctx context.Context, tableName string) ([]string, error) {
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	var tenants []string
+	for obj := range s.client.ListObjects(s.bucket, "", false, doneCh) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+		if obj.Key == "" {
+			continue
+		}
+		tenants = append(tenants, strings.TrimSuffix(obj.Key, "/"))
+	}
+	return tenants, nil
+}
+
+// ScanShard calls callback for every chunk belonging to userID whose
+// object key falls under the given shard, one of the 240 two-hex-character
+// fingerprint prefixes described on bigtableObjectClient.NewScanner.
+func (s *scanner) ScanShard(ctx context.Context, tableName, userID string, shardNum int, callback func(chunk.Chunk) bool) error {
+	if shardNum < 0 || shardNum >= len(shard.Prefixes) {
+		return fmt.Errorf("shard %d out of range [0,%d)", shardNum, len(shard.Prefixes))
+	}
+	return s.scanPrefix(ctx, userID+"/"+shard.Prefixes[shardNum], callback)
+}
+
+func (s *scanner) scanPrefix(ctx context.Context, prefix string, callback func(chunk.Chunk) bool) error {
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	for obj := range s.client.ListObjects(s.bucket, prefix, true, doneCh) {
+		if obj.Err != nil {
+			return obj.Err
+		}
+
+		c, err := chunk.ParseExternalKey("", obj.Key)
+		if err != nil {
+			return err
+		}
+
+		buf, err := getObject(ctx, s.client, s.bucket, obj.Key)
+		if err != nil {
+			return err
+		}
+
+		if err := c.Decode(chunk.NewDecodeContext(), buf); err != nil {
+			return err
+		}
+		if !callback(c) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func getObject(ctx context.Context, client *minio.Client, bucket, key string) ([]byte, error) {
+	obj, err := client.GetObjectWithContext(ctx, bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+
+	return ioutil.ReadAll(obj)
+}
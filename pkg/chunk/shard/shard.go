@@ -0,0 +1,20 @@
+// Package shard holds the fingerprint-prefix sharding scheme shared by
+// every chunk.Scanner implementation (gcp, s3, azure), so a shard number
+// means the same keyspace slice regardless of which backend is scanning
+// it.
+package shard
+
+// Prefixes are the 240 two-hex-character fingerprint prefixes a shard
+// maps onto, as documented on bigtableObjectClient.NewScanner.
+var Prefixes = buildPrefixes()
+
+func buildPrefixes() []string {
+	const hexDigits = "0123456789abcdef"
+	prefixes := make([]string, 0, 240)
+	for _, hi := range hexDigits[1:] { // fingerprints never lead with '0'
+		for _, lo := range hexDigits {
+			prefixes = append(prefixes, string(hi)+string(lo))
+		}
+	}
+	return prefixes
+}
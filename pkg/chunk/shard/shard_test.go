@@ -0,0 +1,15 @@
+package shard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefixes(t *testing.T) {
+	require.Len(t, Prefixes, 240)
+	for _, prefix := range Prefixes {
+		require.Len(t, prefix, 2)
+		require.NotEqual(t, byte('0'), prefix[0])
+	}
+}
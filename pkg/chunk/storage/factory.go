@@ -0,0 +1,106 @@
+// Package storage builds the chunk.Client for whichever object storage
+// engine is selected by Config.Engine, so callers (the compactor,
+// migrate-reader, etc.) don't need a per-backend switch of their own.
+package storage
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/cortexproject/cortex/pkg/chunk/azure"
+	"github.com/cortexproject/cortex/pkg/chunk/gcp"
+	"github.com/cortexproject/cortex/pkg/chunk/s3"
+)
+
+// Config selects and configures the object storage engine chunk.Clients
+// are built against.
+type Config struct {
+	Engine string `yaml:"engine"`
+
+	GCPConfig   gcp.Config   `yaml:"bigtable"`
+	AzureConfig azure.Config `yaml:"azure"`
+	S3Config    s3.Config    `yaml:"s3"`
+}
+
+// RegisterFlags adds the flags required to configure this flag set.
+func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
+	f.StringVar(&cfg.Engine, "storage.engine", "bigtable", "Which storage engine to use for chunks: bigtable, azure or s3.")
+	cfg.GCPConfig.RegisterFlags(f)
+	cfg.AzureConfig.RegisterFlags(f)
+	cfg.S3Config.RegisterFlags(f)
+}
+
+// RegisterFlagsWithPrefix adds the flags required to configure this flag
+// set, each name rooted at prefix instead of the fixed names RegisterFlags
+// uses, so a second Config - e.g. a migration's destination store,
+// alongside the source Config registered by RegisterFlags - can be
+// registered on the same FlagSet without colliding with the first.
+func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.StringVar(&cfg.Engine, prefix+".engine", "bigtable", "Which storage engine to use for chunks: bigtable, azure or s3.")
+	cfg.GCPConfig.RegisterFlagsWithPrefix(prefix+".bigtable", f)
+	cfg.AzureConfig.RegisterFlagsWithPrefix(prefix+".azure", f)
+	cfg.S3Config.RegisterFlagsWithPrefix(prefix+".s3", f)
+}
+
+// NamedStorageClient pairs a chunk.Client with the engine name it was
+// built for. Opts returns a slice, rather than a bare client, to match the
+// shape its callers already index into (storageOpts[0]); this package
+// only ever configures the one engine named by Config.Engine, so that
+// slice is always a single element.
+type NamedStorageClient struct {
+	Name   string
+	Client chunk.Client
+}
+
+// Opts builds the chunk.Client for cfg.Engine.
+func Opts(cfg Config, schemaCfg chunk.SchemaConfig) ([]NamedStorageClient, error) {
+	client, err := newObjectClient(cfg, schemaCfg)
+	if err != nil {
+		return nil, err
+	}
+	return []NamedStorageClient{{Name: cfg.Engine, Client: client}}, nil
+}
+
+func newObjectClient(cfg Config, schemaCfg chunk.SchemaConfig) (chunk.Client, error) {
+	switch cfg.Engine {
+	case "bigtable", "gcp":
+		return gcp.NewBigtableObjectClient(context.Background(), cfg.GCPConfig, schemaCfg)
+	case "azure":
+		return azure.NewObjectClient(cfg.AzureConfig, schemaCfg)
+	case "s3":
+		return s3.NewObjectClient(cfg.S3Config, schemaCfg)
+	default:
+		return nil, fmt.Errorf("unrecognised storage engine %q", cfg.Engine)
+	}
+}
+
+// NewTableClient builds the chunk.TableClient for cfg.Engine. Azure Blob
+// Storage and S3 are plain object stores with no notion of a table to
+// list or create, so only bigtable supports this.
+func NewTableClient(ctx context.Context, cfg Config) (chunk.TableClient, error) {
+	switch cfg.Engine {
+	case "bigtable", "gcp":
+		return gcp.NewTableClient(ctx, cfg.GCPConfig)
+	case "azure", "s3":
+		return nil, fmt.Errorf("storage engine %q has no table client", cfg.Engine)
+	default:
+		return nil, fmt.Errorf("unrecognised storage engine %q", cfg.Engine)
+	}
+}
+
+// NewIndexClient builds the chunk.IndexClient for cfg.Engine. Azure Blob
+// Storage and S3 have no separate index to speak of - DeleteChunk already
+// removes the only copy of a chunk's data for those backends - so only
+// bigtable supports this, the same restriction as NewTableClient.
+func NewIndexClient(ctx context.Context, cfg Config) (chunk.IndexClient, error) {
+	switch cfg.Engine {
+	case "bigtable", "gcp":
+		return gcp.NewBigtableIndexClient(ctx, cfg.GCPConfig)
+	case "azure", "s3":
+		return nil, fmt.Errorf("storage engine %q has no index client", cfg.Engine)
+	default:
+		return nil, fmt.Errorf("unrecognised storage engine %q", cfg.Engine)
+	}
+}
@@ -0,0 +1,115 @@
+// Package compactor implements a retention sweep for the chunk store,
+// analogous to Loki's compactor: it periodically scans index tables for
+// chunks whose owning tenant's retention window has elapsed and removes
+// them (and their index entries) from the backing store.
+package compactor
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/cortexproject/cortex/pkg/util"
+	"github.com/weaveworks/common/server"
+)
+
+// Compactor periodically sweeps index tables for expired chunks.
+type Compactor struct {
+	cfg         Config
+	tableClient chunk.TableClient
+	scanner     chunk.Scanner
+	client      chunk.Client
+	indexClient chunk.IndexClient
+	limits      Limits
+	metrics     *metrics
+	elector     leaderElector
+
+	quit chan struct{}
+	done chan struct{}
+}
+
+// New creates a Compactor. Call Run to start the periodic sweep.
+func New(cfg Config, tableClient chunk.TableClient, scanner chunk.Scanner, client chunk.Client, indexClient chunk.IndexClient, limits Limits, registerer prometheus.Registerer) *Compactor {
+	return &Compactor{
+		cfg:         cfg,
+		tableClient: tableClient,
+		scanner:     scanner,
+		client:      client,
+		indexClient: indexClient,
+		limits:      limits,
+		metrics:     newMetrics(registerer),
+		elector:     newFileElector(cfg.LockFilePath),
+		quit:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// RegisterRoutes wires the compactor's status endpoint into the server, so
+// it can be run as a regular module alongside the rest of Cortex.
+func (c *Compactor) RegisterRoutes(s *server.Server) {
+	s.HTTP.Path("/compactor/ring").Methods(http.MethodGet).HandlerFunc(c.statusHandler)
+}
+
+func (c *Compactor) statusHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("compactor is running\n"))
+}
+
+// Run blocks, triggering a sweep every cfg.Interval, until ctx is cancelled
+// or Stop is called.
+func (c *Compactor) Run(ctx context.Context) error {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.runOnce(ctx); err != nil {
+				level.Error(util.Logger).Log("msg", "retention sweep failed", "err", err)
+			}
+		case <-c.quit:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Stop asks the compactor to stop and waits for any in-flight sweep to finish.
+func (c *Compactor) Stop() {
+	close(c.quit)
+	<-c.done
+}
+
+func (c *Compactor) runOnce(ctx context.Context) error {
+	release, acquired, err := c.elector.tryAcquire()
+	if err != nil {
+		return err
+	}
+	if !acquired {
+		level.Debug(util.Logger).Log("msg", "skipping sweep, another compactor holds the lock")
+		return nil
+	}
+	defer release() // nolint:errcheck
+
+	start := time.Now()
+	defer func() { c.metrics.sweepDuration.Observe(time.Since(start).Seconds()) }()
+
+	tables, err := c.tableClient.ListTables(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		if err := c.sweepTable(ctx, table, start); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package compactor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/cortexproject/cortex/pkg/chunk/testutils"
+)
+
+type fakeScanner struct {
+	chunk.Scanner
+	chunks []chunk.Chunk
+}
+
+func (f *fakeScanner) ScanTable(ctx context.Context, tableName string, callback func(chunk.Chunk) bool) error {
+	for _, c := range f.chunks {
+		if !callback(c) {
+			break
+		}
+	}
+	return nil
+}
+
+type fakeClient struct {
+	chunk.Client
+	deleted []string
+	failOn  func(chunkID string) bool
+}
+
+var errTestDeleteChunk = errors.New("delete chunk failed")
+
+func (f *fakeClient) DeleteChunk(ctx context.Context, chunkID string) error {
+	if f.failOn != nil && f.failOn(chunkID) {
+		return errTestDeleteChunk
+	}
+	f.deleted = append(f.deleted, chunkID)
+	return nil
+}
+
+type fakeWriteBatch struct {
+	deletes []string
+}
+
+func (b *fakeWriteBatch) Delete(tableName, hashValue string, rangeValue []byte) {
+	b.deletes = append(b.deletes, string(rangeValue))
+}
+
+type fakeIndexClient struct {
+	batches []*fakeWriteBatch
+}
+
+func (f *fakeIndexClient) NewWriteBatch() chunk.WriteBatch {
+	return &fakeWriteBatch{}
+}
+
+func (f *fakeIndexClient) BatchWrite(ctx context.Context, batch chunk.WriteBatch) error {
+	f.batches = append(f.batches, batch.(*fakeWriteBatch))
+	return nil
+}
+
+func newTestCompactor(scanner chunk.Scanner, client chunk.Client, indexClient chunk.IndexClient, limits Limits) *Compactor {
+	return &Compactor{
+		cfg:         Config{DeleteBatchSize: 100},
+		scanner:     scanner,
+		client:      client,
+		indexClient: indexClient,
+		limits:      limits,
+		metrics:     newMetrics(nil),
+		elector:     noopElector{},
+	}
+}
+
+func TestSweepTableDeletesOnlyExpiredChunks(t *testing.T) {
+	now := model.Now()
+	_, expired, _ := testutils.CreateChunks(1, 1, testutils.UserOpt("expired-tenant"), testutils.From(now.Add(-60*24*time.Hour)))
+	_, fresh, _ := testutils.CreateChunks(1, 1, testutils.UserOpt("fresh-tenant"), testutils.From(now))
+
+	scanner := &fakeScanner{chunks: append(expired, fresh...)}
+	client := &fakeClient{}
+	indexClient := &fakeIndexClient{}
+	limits := NewOverrides(30*24*time.Hour, nil)
+
+	c := newTestCompactor(scanner, client, indexClient, limits)
+	require.NoError(t, c.sweepTable(context.Background(), "table", now.Time()))
+
+	require.Len(t, client.deleted, 1)
+	require.Equal(t, expired[0].ExternalKey(), client.deleted[0])
+	require.Len(t, indexClient.batches, 1)
+	require.Equal(t, []string{expired[0].ExternalKey()}, indexClient.batches[0].deletes)
+}
+
+func TestSweepTableDryRunDeletesNothing(t *testing.T) {
+	now := model.Now()
+	_, expired, _ := testutils.CreateChunks(1, 1, testutils.UserOpt("expired-tenant"), testutils.From(now.Add(-60*24*time.Hour)))
+
+	scanner := &fakeScanner{chunks: expired}
+	client := &fakeClient{}
+	indexClient := &fakeIndexClient{}
+	limits := NewOverrides(30*24*time.Hour, nil)
+
+	c := newTestCompactor(scanner, client, indexClient, limits)
+	c.cfg.DryRun = true
+
+	require.NoError(t, c.sweepTable(context.Background(), "table", now.Time()))
+	require.Empty(t, client.deleted)
+	require.Empty(t, indexClient.batches)
+}
+
+func TestDeleteChunksFlushesIndexForChunksDeletedBeforeAFailure(t *testing.T) {
+	now := model.Now()
+	_, expired, _ := testutils.CreateChunks(1, 3, testutils.UserOpt("expired-tenant"), testutils.From(now.Add(-60*24*time.Hour)))
+
+	failingChunkID := expired[1].ExternalKey()
+	client := &fakeClient{failOn: func(chunkID string) bool { return chunkID == failingChunkID }}
+	indexClient := &fakeIndexClient{}
+
+	c := newTestCompactor(&fakeScanner{}, client, indexClient, NewOverrides(30*24*time.Hour, nil))
+	err := c.deleteChunks(context.Background(), "table", expired)
+	require.Equal(t, errTestDeleteChunk, err)
+
+	// The chunk deleted before the failure must still have its index
+	// entry scheduled for deletion, even though DeleteChunk failed
+	// partway through the batch.
+	require.Equal(t, []string{expired[0].ExternalKey()}, client.deleted)
+	require.Len(t, indexClient.batches, 1)
+	require.Equal(t, []string{expired[0].ExternalKey()}, indexClient.batches[0].deletes)
+}
+
+func TestRunOnceSkipsWhenLockHeld(t *testing.T) {
+	now := model.Now()
+	_, expired, _ := testutils.CreateChunks(1, 1, testutils.UserOpt("expired-tenant"), testutils.From(now.Add(-60*24*time.Hour)))
+
+	scanner := &fakeScanner{chunks: expired}
+	client := &fakeClient{}
+	indexClient := &fakeIndexClient{}
+	c := newTestCompactor(scanner, client, indexClient, NewOverrides(time.Hour, nil))
+	c.tableClient = fakeTableClient{}
+	c.elector = lockedElector{}
+
+	require.NoError(t, c.runOnce(context.Background()))
+	require.Empty(t, client.deleted)
+	require.Empty(t, indexClient.batches)
+}
+
+type fakeTableClient struct {
+	chunk.TableClient
+}
+
+func (fakeTableClient) ListTables(ctx context.Context) ([]string, error) {
+	return []string{"table"}, nil
+}
+
+type lockedElector struct{}
+
+func (lockedElector) tryAcquire() (func() error, bool, error) {
+	return nil, false, nil
+}
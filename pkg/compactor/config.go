@@ -0,0 +1,26 @@
+package compactor
+
+import (
+	"flag"
+	"time"
+)
+
+// Config configures the compactor.
+type Config struct {
+	Enabled                bool          `yaml:"enabled"`
+	Interval               time.Duration `yaml:"interval"`
+	DryRun                 bool          `yaml:"dry_run"`
+	LockFilePath           string        `yaml:"lock_file_path"`
+	DeleteBatchSize        int           `yaml:"delete_batch_size"`
+	DefaultRetentionPeriod time.Duration `yaml:"default_retention_period"`
+}
+
+// RegisterFlags adds the flags required to configure this flag set.
+func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, "compactor.enabled", false, "Enable the retention compactor.")
+	f.DurationVar(&cfg.Interval, "compactor.interval", 2*time.Hour, "How often to run the retention sweep.")
+	f.BoolVar(&cfg.DryRun, "compactor.dry-run", false, "Log how many expired chunks each batch would delete, without deleting them or their index entries.")
+	f.StringVar(&cfg.LockFilePath, "compactor.lock-file", "", "Path to a lock file used to ensure only one compactor runs the sweep at a time. Leave empty to disable leader election.")
+	f.IntVar(&cfg.DeleteBatchSize, "compactor.delete-batch-size", 100, "Number of expired chunks to collect before deleting them and their index entries in one batch.")
+	f.DurationVar(&cfg.DefaultRetentionPeriod, "compactor.default-retention-period", 31*24*time.Hour, "How long to keep chunks for tenants with no retention override configured.")
+}
@@ -0,0 +1,36 @@
+package compactor
+
+import "time"
+
+// Limits defines the per-tenant limits the compactor needs to decide what
+// may be deleted. It follows the same shape as the Overrides type used
+// elsewhere in Cortex so the retention window can be tuned per-tenant
+// through the runtime config overlay rather than requiring a restart.
+type Limits interface {
+	RetentionPeriod(userID string) time.Duration
+}
+
+// Overrides is the default Limits implementation: a global retention
+// period with optional per-tenant overrides.
+type Overrides struct {
+	defaultRetentionPeriod time.Duration
+	perTenantOverrides     map[string]time.Duration
+}
+
+// NewOverrides builds an Overrides from a default retention period and a
+// map of per-tenant overrides.
+func NewOverrides(defaultRetentionPeriod time.Duration, perTenantOverrides map[string]time.Duration) *Overrides {
+	return &Overrides{
+		defaultRetentionPeriod: defaultRetentionPeriod,
+		perTenantOverrides:     perTenantOverrides,
+	}
+}
+
+// RetentionPeriod returns how long chunks belonging to userID should be
+// kept before the compactor is allowed to delete them.
+func (o *Overrides) RetentionPeriod(userID string) time.Duration {
+	if period, ok := o.perTenantOverrides[userID]; ok {
+		return period
+	}
+	return o.defaultRetentionPeriod
+}
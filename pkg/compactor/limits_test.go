@@ -0,0 +1,17 @@
+package compactor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverridesRetentionPeriod(t *testing.T) {
+	o := NewOverrides(24*time.Hour, map[string]time.Duration{
+		"tenant-a": 72 * time.Hour,
+	})
+
+	require.Equal(t, 72*time.Hour, o.RetentionPeriod("tenant-a"))
+	require.Equal(t, 24*time.Hour, o.RetentionPeriod("tenant-b"))
+}
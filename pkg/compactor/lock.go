@@ -0,0 +1,58 @@
+package compactor
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// leaderElector decides whether this process is allowed to run a sweep.
+// The default implementation is a file lock, which is enough to guarantee
+// single-writer semantics on a single host; a ring-based elector can be
+// dropped in behind this interface for multi-replica deployments.
+type leaderElector interface {
+	// tryAcquire returns a release func on success, or false if another
+	// instance currently holds leadership.
+	tryAcquire() (release func() error, acquired bool, err error)
+}
+
+// noopElector always grants leadership, used when no lock file is configured.
+type noopElector struct{}
+
+func (noopElector) tryAcquire() (func() error, bool, error) {
+	return func() error { return nil }, true, nil
+}
+
+// fileElector grants leadership to whichever process holds an exclusive
+// lock on a well-known file.
+type fileElector struct {
+	path string
+}
+
+func newFileElector(path string) leaderElector {
+	if path == "" {
+		return noopElector{}
+	}
+	return &fileElector{path: path}
+}
+
+func (e *fileElector) tryAcquire() (func() error, bool, error) {
+	f, err := os.OpenFile(e.path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, false, fmt.Errorf("opening compactor lock file: %w", err)
+	}
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		return nil, false, nil
+	}
+
+	release := func() error {
+		if err := syscall.Flock(int(f.Fd()), syscall.LOCK_UN); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+	return release, true, nil
+}
@@ -0,0 +1,36 @@
+package compactor
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoopElectorAlwaysAcquires(t *testing.T) {
+	e := newFileElector("")
+	release, acquired, err := e.tryAcquire()
+	require.NoError(t, err)
+	require.True(t, acquired)
+	require.NoError(t, release())
+}
+
+func TestFileElectorIsExclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "compactor.lock")
+	e := newFileElector(path)
+
+	release, acquired, err := e.tryAcquire()
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	_, acquired, err = e.tryAcquire()
+	require.NoError(t, err)
+	require.False(t, acquired, "a second acquire should fail while the first lock is held")
+
+	require.NoError(t, release())
+
+	release, acquired, err = e.tryAcquire()
+	require.NoError(t, err)
+	require.True(t, acquired, "acquiring should succeed again once the lock is released")
+	require.NoError(t, release())
+}
@@ -0,0 +1,59 @@
+package compactor
+
+import "github.com/prometheus/client_golang/prometheus"
+
+type metrics struct {
+	chunksScanned       prometheus.Counter
+	chunksDeleted       prometheus.Counter
+	indexEntriesDeleted prometheus.Counter
+	sweepDuration       prometheus.Histogram
+	tableLagSeconds     *prometheus.GaugeVec
+}
+
+func newMetrics(r prometheus.Registerer) *metrics {
+	m := &metrics{
+		chunksScanned: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "cortex",
+			Subsystem: "compactor",
+			Name:      "chunks_scanned_total",
+			Help:      "Total number of chunks scanned while looking for expired chunks.",
+		}),
+		chunksDeleted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "cortex",
+			Subsystem: "compactor",
+			Name:      "chunks_deleted_total",
+			Help:      "Total number of chunks deleted because they were past their retention period.",
+		}),
+		indexEntriesDeleted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "cortex",
+			Subsystem: "compactor",
+			Name:      "index_entries_deleted_total",
+			Help:      "Total number of index entries deleted alongside expired chunks.",
+		}),
+		sweepDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "cortex",
+			Subsystem: "compactor",
+			Name:      "sweep_duration_seconds",
+			Help:      "Time taken to complete a full retention sweep.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 10),
+		}),
+		tableLagSeconds: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "cortex",
+			Subsystem: "compactor",
+			Name:      "table_lag_seconds",
+			Help:      "Age of the oldest chunk found in a table during the last sweep, counting chunks that sweep deleted.",
+		}, []string{"table"}),
+	}
+
+	if r != nil {
+		r.MustRegister(
+			m.chunksScanned,
+			m.chunksDeleted,
+			m.indexEntriesDeleted,
+			m.sweepDuration,
+			m.tableLagSeconds,
+		)
+	}
+
+	return m
+}
@@ -0,0 +1,110 @@
+package compactor
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/log/level"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/cortexproject/cortex/pkg/util"
+)
+
+// sweepTable scans a single index table via the supplied chunk.Scanner,
+// and deletes any chunk (and its index entries) whose Through timestamp is
+// older than the owning tenant's retention period.
+func (c *Compactor) sweepTable(ctx context.Context, tableName string, now time.Time) error {
+	var (
+		expired  = make([]chunk.Chunk, 0, c.cfg.DeleteBatchSize)
+		oldest   = now
+		scanErrs []error
+	)
+
+	flush := func() error {
+		if len(expired) == 0 {
+			return nil
+		}
+		if err := c.deleteChunks(ctx, tableName, expired); err != nil {
+			return err
+		}
+		expired = expired[:0]
+		return nil
+	}
+
+	err := c.scanner.ScanTable(ctx, tableName, func(ch chunk.Chunk) bool {
+		c.metrics.chunksScanned.Inc()
+
+		retention := c.limits.RetentionPeriod(ch.UserID)
+		cutoff := now.Add(-retention)
+
+		if ch.Through.Time().Before(oldest) {
+			oldest = ch.Through.Time()
+		}
+
+		if ch.Through.Time().After(cutoff) {
+			return true
+		}
+
+		expired = append(expired, ch)
+		if len(expired) >= c.cfg.DeleteBatchSize {
+			if err := flush(); err != nil {
+				scanErrs = append(scanErrs, err)
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	for _, err := range scanErrs {
+		if err != nil {
+			return err
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	c.metrics.tableLagSeconds.WithLabelValues(tableName).Set(now.Sub(oldest).Seconds())
+	return nil
+}
+
+func (c *Compactor) deleteChunks(ctx context.Context, tableName string, expired []chunk.Chunk) error {
+	if c.cfg.DryRun {
+		level.Info(util.Logger).Log("msg", "dry-run: would delete expired chunks", "table", tableName, "count", len(expired))
+		return nil
+	}
+
+	batch := c.indexClient.NewWriteBatch()
+	chunkIDs := make([]string, 0, len(expired))
+	deleteErr := error(nil)
+	for _, ch := range expired {
+		chunkID := ch.ExternalKey()
+		if err := c.client.DeleteChunk(ctx, chunkID); err != nil {
+			deleteErr = err
+			break
+		}
+		batch.Delete(tableName, ch.UserID, []byte(chunkID))
+		chunkIDs = append(chunkIDs, chunkID)
+		c.metrics.chunksDeleted.Inc()
+	}
+
+	// Chunks deleted before a failure already have their data gone, so
+	// their index entries must still be scheduled for deletion even
+	// though the batch is short of the full set - otherwise they're left
+	// as dangling index rows that later query paths hit as "chunk not
+	// found".
+	if len(chunkIDs) > 0 {
+		if err := c.indexClient.BatchWrite(ctx, batch); err != nil {
+			return err
+		}
+		c.metrics.indexEntriesDeleted.Add(float64(len(chunkIDs)))
+	}
+	if deleteErr != nil {
+		return deleteErr
+	}
+
+	level.Debug(util.Logger).Log("msg", "deleted expired chunks", "table", tableName, "count", len(chunkIDs))
+	return nil
+}
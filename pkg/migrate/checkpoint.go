@@ -0,0 +1,76 @@
+package migrate
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// checkpoint tracks which shards have already been fully transferred, so a
+// failed or interrupted run of TransferData can resume without re-reading
+// shards it already completed. It is persisted as a small JSON file rather
+// than an object so it can be inspected and edited by hand if needed.
+type checkpoint struct {
+	path string
+
+	mtx  sync.Mutex
+	done map[string]bool
+}
+
+type checkpointState struct {
+	CompletedShards []string `json:"completed_shards"`
+}
+
+func loadCheckpoint(path string) (*checkpoint, error) {
+	cp := &checkpoint{path: path, done: map[string]bool{}}
+	if path == "" {
+		return cp, nil
+	}
+
+	buf, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cp, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if len(buf) == 0 {
+		return cp, nil
+	}
+
+	var state checkpointState
+	if err := json.Unmarshal(buf, &state); err != nil {
+		return nil, err
+	}
+	for _, key := range state.CompletedShards {
+		cp.done[key] = true
+	}
+	return cp, nil
+}
+
+func (c *checkpoint) isDone(key string) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.done[key]
+}
+
+func (c *checkpoint) markDone(key string) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.done[key] = true
+
+	if c.path == "" {
+		return nil
+	}
+
+	state := checkpointState{CompletedShards: make([]string, 0, len(c.done))}
+	for key := range c.done {
+		state.CompletedShards = append(state.CompletedShards, key)
+	}
+
+	buf, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.path, buf, 0644)
+}
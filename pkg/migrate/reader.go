@@ -0,0 +1,283 @@
+// Package migrate implements the transfer pipeline behind the migrate
+// tool: a Reader that streams chunks out of a source chunk store, and a
+// Writer that streams them into a destination.
+package migrate
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/cortexproject/cortex/pkg/chunk/shard"
+	"github.com/cortexproject/cortex/pkg/util"
+)
+
+const defaultBatchSize = 100
+
+// ReaderConfig configures the sharded Reader.
+type ReaderConfig struct {
+	ShardRange     string `yaml:"shard_range"`
+	Parallelism    int    `yaml:"parallelism"`
+	BatchSize      int    `yaml:"batch_size"`
+	CheckpointPath string `yaml:"checkpoint_path"`
+}
+
+// RegisterFlags adds the flags required to configure this flag set.
+func (cfg *ReaderConfig) RegisterFlags(f *flag.FlagSet) {
+	f.StringVar(&cfg.ShardRange, "migrate.shard-range", "0-239", "Inclusive range of shards this reader is responsible for, out of the fixed shard.Prefixes keyspace (240 shards, 0-239), e.g. \"0-119\".")
+	f.IntVar(&cfg.Parallelism, "migrate.parallelism", 16, "Number of shards to scan concurrently.")
+	f.IntVar(&cfg.BatchSize, "migrate.batch-size", defaultBatchSize, "Number of chunks to buffer before handing a batch to the writer.")
+	f.StringVar(&cfg.CheckpointPath, "migrate.checkpoint-path", "", "Path to a file recording every shard completed for each table and user, so an interrupted transfer can resume. Leave empty to disable.")
+}
+
+func (cfg *ReaderConfig) shardBounds() (first, last int, err error) {
+	parts := strings.SplitN(cfg.ShardRange, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid shard range %q, expected \"first-last\"", cfg.ShardRange)
+	}
+
+	first, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid shard range %q: %w", cfg.ShardRange, err)
+	}
+	last, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid shard range %q: %w", cfg.ShardRange, err)
+	}
+	if first < 0 || last >= len(shard.Prefixes) || first > last {
+		return 0, 0, fmt.Errorf("shard range %q out of bounds for %d shards", cfg.ShardRange, len(shard.Prefixes))
+	}
+	return first, last, nil
+}
+
+// scanClient is satisfied by chunk store clients, such as
+// bigtableObjectClient, that can produce a sharded chunk.Scanner.
+type scanClient interface {
+	chunk.Client
+	NewScanner() chunk.Scanner
+}
+
+// Reader reads chunks out of a source chunk store, sharding the keyspace
+// by fingerprint prefix so that shards can be scanned in parallel.
+type Reader struct {
+	cfg         ReaderConfig
+	client      scanClient
+	tableClient chunk.TableClient
+	writer      Writer
+
+	checkpoint *checkpoint
+	metrics    *readerMetrics
+}
+
+// NewReader creates a Reader that scans every table reported by
+// tableClient, sharded by fingerprint prefix, and streams the chunks it
+// finds into writer.
+func NewReader(cfg ReaderConfig, client scanClient, tableClient chunk.TableClient, writer Writer) (*Reader, error) {
+	if _, _, err := cfg.shardBounds(); err != nil {
+		return nil, err
+	}
+
+	cp, err := loadCheckpoint(cfg.CheckpointPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reader{
+		cfg:         cfg,
+		client:      client,
+		tableClient: tableClient,
+		writer:      writer,
+		checkpoint:  cp,
+		metrics:     newReaderMetrics(prometheus.DefaultRegisterer),
+	}, nil
+}
+
+// shardKey identifies one (table, userID, shard) unit of work, and is the
+// unit the checkpoint tracks so a resumed run skips exactly what already
+// completed. Shards only partition a single tenant's keyspace, so the
+// tenant has to be part of the key.
+type shardKey struct {
+	table  string
+	userID string
+	shard  int
+}
+
+func (k shardKey) String() string { return fmt.Sprintf("%s/%s/%d", k.table, k.userID, k.shard) }
+
+// labels returns the Prometheus label set identifying this shard in the
+// per-shard gauges below.
+func (k shardKey) labels() prometheus.Labels {
+	return prometheus.Labels{"table": k.table, "user": k.userID, "shard": strconv.Itoa(k.shard)}
+}
+
+// TransferData scans every shard of every tenant of every table reported
+// by the table client, in parallel up to cfg.Parallelism, and hands
+// batches of chunks to the writer.
+func (r *Reader) TransferData(ctx context.Context) error {
+	first, last, err := r.cfg.shardBounds()
+	if err != nil {
+		return err
+	}
+
+	tables, err := r.tableClient.ListTables(ctx)
+	if err != nil {
+		return err
+	}
+
+	scanner := r.client.NewScanner()
+
+	var keys []shardKey
+	for _, table := range tables {
+		userIDs, err := scanner.ListTenants(ctx, table)
+		if err != nil {
+			return fmt.Errorf("table %s: listing tenants: %w", table, err)
+		}
+
+		for _, userID := range userIDs {
+			for shard := first; shard <= last; shard++ {
+				keys = append(keys, shardKey{table: table, userID: userID, shard: shard})
+			}
+		}
+	}
+
+	sem := make(chan struct{}, r.cfg.Parallelism)
+	errs := make(chan error, len(keys))
+	var wg sync.WaitGroup
+
+	for _, key := range keys {
+		if r.checkpoint.isDone(key.String()) {
+			level.Info(util.Logger).Log("msg", "skipping already completed shard", "table", key.table, "user", key.userID, "shard", key.shard)
+			continue
+		}
+
+		key := key
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := r.transferShard(ctx, scanner, key); err != nil {
+				errs <- fmt.Errorf("table %s user %s shard %d: %w", key.table, key.userID, key.shard, err)
+				return
+			}
+			if err := r.checkpoint.markDone(key.String()); err != nil {
+				errs <- fmt.Errorf("table %s user %s shard %d: checkpointing: %w", key.table, key.userID, key.shard, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Reader) transferShard(ctx context.Context, scanner chunk.Scanner, key shardKey) error {
+	labels := key.labels()
+	r.metrics.shardStartedAt.With(labels).Set(float64(time.Now().Unix()))
+	defer r.metrics.shardStartedAt.Delete(labels)
+	defer r.metrics.shardChunksTransferred.Delete(labels)
+
+	var transferred int
+	batch := make([]chunk.Chunk, 0, r.cfg.BatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := r.writer.WriteChunks(ctx, batch); err != nil {
+			return err
+		}
+		r.metrics.chunksTransferred.Add(float64(len(batch)))
+		transferred += len(batch)
+		r.metrics.shardChunksTransferred.With(labels).Set(float64(transferred))
+		batch = batch[:0]
+		return nil
+	}
+
+	var flushErr error
+	err := scanner.ScanShard(ctx, key.table, key.userID, key.shard, func(c chunk.Chunk) bool {
+		batch = append(batch, c)
+		if len(batch) >= r.cfg.BatchSize {
+			if err := flush(); err != nil {
+				level.Error(util.Logger).Log("msg", "failed to flush batch", "table", key.table, "shard", key.shard, "err", err)
+				flushErr = err
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if flushErr != nil {
+		return flushErr
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	r.metrics.shardsCompleted.Inc()
+	return nil
+}
+
+type readerMetrics struct {
+	chunksTransferred prometheus.Counter
+	shardsCompleted   prometheus.Counter
+
+	// shardChunksTransferred and shardStartedAt are per in-flight-shard
+	// gauges: at most cfg.Parallelism label sets exist at once, since
+	// transferShard deletes its labels on return, so these stay bounded
+	// even though 240 shards per tenant per table can run over the life of
+	// a transfer.
+	// They exist so an operator can see which of the concurrently
+	// running shards is slow or stuck, which the two aggregate counters
+	// above can't show.
+	shardChunksTransferred *prometheus.GaugeVec
+	shardStartedAt         *prometheus.GaugeVec
+}
+
+func newReaderMetrics(r prometheus.Registerer) *readerMetrics {
+	m := &readerMetrics{
+		chunksTransferred: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "cortex",
+			Subsystem: "migrate",
+			Name:      "chunks_transferred_total",
+			Help:      "Total number of chunks read from the source and handed to the writer.",
+		}),
+		shardsCompleted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "cortex",
+			Subsystem: "migrate",
+			Name:      "shards_completed_total",
+			Help:      "Total number of shards fully transferred.",
+		}),
+		shardChunksTransferred: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "cortex",
+			Subsystem: "migrate",
+			Name:      "shard_chunks_transferred",
+			Help:      "Number of chunks transferred so far by the currently in-flight shard transfer.",
+		}, []string{"table", "user", "shard"}),
+		shardStartedAt: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "cortex",
+			Subsystem: "migrate",
+			Name:      "shard_started_timestamp_seconds",
+			Help:      "Unix timestamp at which the currently in-flight shard transfer started.",
+		}, []string{"table", "user", "shard"}),
+	}
+	if r != nil {
+		r.MustRegister(m.chunksTransferred, m.shardsCompleted, m.shardChunksTransferred, m.shardStartedAt)
+	}
+	return m
+}
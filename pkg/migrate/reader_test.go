@@ -0,0 +1,50 @@
+package migrate
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardBounds(t *testing.T) {
+	for _, tc := range []struct {
+		cfg         ReaderConfig
+		first, last int
+		wantErr     bool
+	}{
+		{cfg: ReaderConfig{ShardRange: "0-119"}, first: 0, last: 119},
+		{cfg: ReaderConfig{ShardRange: "120-239"}, first: 120, last: 239},
+		{cfg: ReaderConfig{ShardRange: "bad"}, wantErr: true},
+		{cfg: ReaderConfig{ShardRange: "5-3"}, wantErr: true},
+		{cfg: ReaderConfig{ShardRange: "0-240"}, wantErr: true},
+	} {
+		first, last, err := tc.cfg.shardBounds()
+		if tc.wantErr {
+			require.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, tc.first, first)
+		require.Equal(t, tc.last, last)
+	}
+}
+
+func TestCheckpointResume(t *testing.T) {
+	f, err := ioutil.TempFile("", "checkpoint")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	require.NoError(t, f.Close())
+
+	cp, err := loadCheckpoint(f.Name())
+	require.NoError(t, err)
+	require.False(t, cp.isDone("chunks_1/7"))
+
+	require.NoError(t, cp.markDone("chunks_1/7"))
+
+	resumed, err := loadCheckpoint(f.Name())
+	require.NoError(t, err)
+	require.True(t, resumed.isDone("chunks_1/7"))
+	require.False(t, resumed.isDone("chunks_1/8"))
+}
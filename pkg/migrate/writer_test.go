@@ -0,0 +1,38 @@
+package migrate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/cortexproject/cortex/pkg/chunk/testutils"
+	"github.com/cortexproject/cortex/pkg/migrate/mapper"
+)
+
+type fakeClient struct {
+	chunk.Client
+	written []chunk.Chunk
+}
+
+func (f *fakeClient) PutChunks(ctx context.Context, chunks []chunk.Chunk) error {
+	f.written = append(f.written, chunks...)
+	return nil
+}
+
+func TestTransferWriterAppliesMapping(t *testing.T) {
+	now := model.Now()
+	_, input, _ := testutils.CreateChunks(1, 1, testutils.UserOpt("1"), testutils.From(now))
+
+	client := &fakeClient{}
+	w := &transferWriter{
+		client: client,
+		mapper: &mapper.Mapper{Users: map[string]string{"1": "2"}},
+	}
+
+	require.NoError(t, w.WriteChunks(context.Background(), input))
+	require.Len(t, client.written, 1)
+	require.Equal(t, "2", client.written[0].UserID)
+}